@@ -0,0 +1,105 @@
+package goseapi
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrBatchOut is returned by Batch and BatchContext when out is not a
+// non-nil pointer to a slice.
+var ErrBatchOut = errors.New("goseapi: Batch out must be a pointer to a slice")
+
+// maxBatchIDs is the most IDs the API accepts in a single {ids} request.
+const maxBatchIDs = 100
+
+// DefaultMaxConcurrency bounds how many chunked requests Batch issues at
+// once when a Client doesn't set MaxConcurrency.
+const DefaultMaxConcurrency = 4
+
+// Batch fetches ids from path, an {ids}-style endpoint, chunking them
+// into groups of at most 100 (the API's limit) and issuing the chunks
+// concurrently. out must be a pointer to a slice; the decoded elements
+// are appended to it in the same order as ids, regardless of the order
+// the chunked requests complete in.
+func (c *Client) Batch(path string, ids []int, out interface{}, params *Params) error {
+	return c.BatchContext(context.Background(), path, ids, out, params)
+}
+
+// BatchContext is Batch with a context for cancellation; see Batch.
+func (c *Client) BatchContext(ctx context.Context, path string, ids []int, out interface{}, params *Params) error {
+	outPtr := reflect.ValueOf(out)
+	if outPtr.Kind() != reflect.Ptr || outPtr.Elem().Kind() != reflect.Slice {
+		return ErrBatchOut
+	}
+	elemType := outPtr.Elem().Type().Elem()
+
+	chunks := chunkIDs(ids, maxBatchIDs)
+	results := make([][]interface{}, len(chunks))
+	errs := make([]error, len(chunks))
+
+	maxConcurrency := DefaultMaxConcurrency
+	if c != nil && c.MaxConcurrency > 0 {
+		maxConcurrency = c.MaxConcurrency
+	}
+	sem := make(chan struct{}, maxConcurrency)
+
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		i, chunk := i, chunk
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			p := *params
+			p.Args = []string{JoinIDs(chunk)}
+
+			slicePtr := reflect.New(reflect.SliceOf(elemType))
+			if _, err := c.DoContext(ctx, path, slicePtr.Interface(), &p); err != nil {
+				errs[i] = err
+				return
+			}
+
+			slice := slicePtr.Elem()
+			items := make([]interface{}, slice.Len())
+			for j := range items {
+				items[j] = slice.Index(j).Interface()
+			}
+			results[i] = items
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	outSlice := outPtr.Elem()
+	for _, items := range results {
+		for _, item := range items {
+			outSlice = reflect.Append(outSlice, reflect.ValueOf(item))
+		}
+	}
+	outPtr.Elem().Set(outSlice)
+	return nil
+}
+
+// chunkIDs splits ids into consecutive groups of at most size elements,
+// preserving order.
+func chunkIDs(ids []int, size int) [][]int {
+	var chunks [][]int
+	for len(ids) > 0 {
+		n := size
+		if n > len(ids) {
+			n = len(ids)
+		}
+		chunks = append(chunks, ids[:n])
+		ids = ids[n:]
+	}
+	return chunks
+}
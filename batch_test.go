@@ -0,0 +1,15 @@
+package goseapi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestBatchRejectsNonSliceOut(t *testing.T) {
+	var c Client
+	var out int
+	err := c.Batch("/questions/{ids}", []int{1, 2}, &out, &Params{Site: StackOverflow})
+	if !errors.Is(err, ErrBatchOut) {
+		t.Fatalf("got err %v, want ErrBatchOut", err)
+	}
+}
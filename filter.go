@@ -0,0 +1,168 @@
+package goseapi
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// PathFilterCreate and PathFilterInfo are the Stack Exchange filter
+// management endpoints.
+const (
+	PathFilterCreate = "/filters/create"
+	PathFilterInfo   = "/filters/{ids}"
+)
+
+// Filter is a compiled field filter as returned by /filters/create.
+type Filter struct {
+	FilterStr      string   `json:"filter"`
+	IncludedFields []string `json:"included_fields"`
+	Unsafe         bool     `json:"unsafe"`
+}
+
+// CreateFilter compiles a filter from the given include/exclude field
+// lists and base filter, registering it with the API so its compiled
+// form (Filter.FilterStr) can be passed as Params.Filter on later calls.
+//
+// base may be "" (equivalent to the "default" filter) or one of the
+// named base filters ("default", "withbody", "none", "total").
+func (c *Client) CreateFilter(include, exclude []string, base string, unsafe bool) (*Filter, error) {
+	vals := (&Params{}).values()
+	if len(include) > 0 {
+		vals.Set("include", strings.Join(include, ";"))
+	}
+	if len(exclude) > 0 {
+		vals.Set("exclude", strings.Join(exclude, ";"))
+	}
+	if base != "" {
+		vals.Set("base", base)
+	}
+	if unsafe {
+		vals.Set("unsafe", "true")
+	}
+
+	var filters []Filter
+	_, err := c.doValues(context.Background(), PathFilterCreate, &filters, vals, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	f := filters[0]
+	c.cacheFilter(f.FilterStr, &f)
+	return &f, nil
+}
+
+// FilterInfo looks up one or more previously compiled filters by their
+// filter string.
+func (c *Client) FilterInfo(ids []string) ([]Filter, error) {
+	var filters []Filter
+	params := &Params{Args: []string{strings.Join(ids, ";")}}
+	_, err := c.Do(PathFilterInfo, &filters, params)
+	return filters, err
+}
+
+// filterCache memoizes compiled filters on the Client, keyed by the set
+// of fields they were derived from, so repeated calls against the same
+// struct type don't recompile (and re-register) the same filter. Filters
+// from a direct CreateFilter call are stored under their filter string.
+type filterCache struct {
+	mu    sync.Mutex
+	byKey map[string]*Filter
+}
+
+func (c *Client) cacheFilter(key string, f *Filter) {
+	c.filters.mu.Lock()
+	defer c.filters.mu.Unlock()
+	if c.filters.byKey == nil {
+		c.filters.byKey = make(map[string]*Filter)
+	}
+	c.filters.byKey[key] = f
+}
+
+// filterFor derives the minimal filter needed to populate v, which must
+// be a pointer to a struct or to a slice of structs, by walking its JSON
+// tags. The result is cached on c so later calls with the same shape
+// reuse the compiled filter instead of recompiling it.
+func (c *Client) filterFor(v interface{}) (string, error) {
+	fields := jsonFields(reflect.TypeOf(v))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	key := strings.Join(fields, ";")
+
+	c.filters.mu.Lock()
+	if f, ok := c.filters.byKey[key]; ok {
+		c.filters.mu.Unlock()
+		return f.FilterStr, nil
+	}
+	c.filters.mu.Unlock()
+
+	f, err := c.CreateFilter(fields, nil, "none", false)
+	if err != nil || f == nil {
+		return "", err
+	}
+	c.cacheFilter(key, f)
+	return f.FilterStr, nil
+}
+
+// jsonFields returns the dotted field names (e.g. "owner.display_name")
+// taken from the json tags of t, unwrapping pointers and slices to reach
+// the underlying struct and recursing into struct-valued fields so
+// nested fields are addressable the way Stack Exchange's filter
+// "include" parameter requires.
+func jsonFields(t reflect.Type) []string {
+	return appendJSONFields(nil, "", t, nil)
+}
+
+func appendJSONFields(fields []string, prefix string, t reflect.Type, seen map[reflect.Type]bool) []string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct || seen[t] {
+		return fields
+	}
+	seen = copySeen(seen, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" {
+			continue
+		}
+		dotted := name
+		if prefix != "" {
+			dotted = prefix + "." + name
+		}
+
+		ft := f.Type
+		for ft != nil && (ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice || ft.Kind() == reflect.Array) {
+			ft = ft.Elem()
+		}
+		if ft != nil && ft.Kind() == reflect.Struct {
+			fields = appendJSONFields(fields, dotted, ft, seen)
+			continue
+		}
+		fields = append(fields, dotted)
+	}
+	return fields
+}
+
+// copySeen returns a copy of seen with t added, so sibling recursive
+// calls don't see types only visited on other branches while a single
+// branch still catches a cycle back to its own ancestor.
+func copySeen(seen map[reflect.Type]bool, t reflect.Type) map[reflect.Type]bool {
+	next := make(map[reflect.Type]bool, len(seen)+1)
+	for k, v := range seen {
+		next[k] = v
+	}
+	next[t] = true
+	return next
+}
@@ -0,0 +1,42 @@
+package goseapi
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type filterTestOwner struct {
+	DisplayName string `json:"display_name"`
+	UserID      int    `json:"user_id"`
+}
+
+type filterTestQuestion struct {
+	Title string          `json:"title"`
+	Owner filterTestOwner `json:"owner"`
+}
+
+func TestJSONFieldsRecursesIntoNestedStructs(t *testing.T) {
+	got := jsonFields(reflect.TypeOf(filterTestQuestion{}))
+	sort.Strings(got)
+
+	want := []string{"owner.display_name", "owner.user_id", "title"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestJSONFieldsHandlesSliceOfStructs(t *testing.T) {
+	got := jsonFields(reflect.TypeOf([]filterTestQuestion{}))
+	sort.Strings(got)
+
+	want := []string{"owner.display_name", "owner.user_id", "title"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
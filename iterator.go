@@ -0,0 +1,154 @@
+package goseapi
+
+import (
+	"context"
+	"reflect"
+)
+
+// Iterate returns an Iterator that walks every page of path, decoding
+// each item as elemType, until the API reports has_more == false. It
+// replaces the common pattern of looping manually on Wrapper.HasMore and
+// incrementing Params.Page.
+//
+// params is copied before use; its Page field is overwritten as the
+// Iterator advances.
+func (c *Client) Iterate(path string, elemType reflect.Type, params *Params) *Iterator {
+	p := *params
+	if p.Page == 0 {
+		p.Page = 1
+	}
+
+	it := &Iterator{
+		c:        c,
+		path:     path,
+		elemType: elemType,
+		params:   p,
+	}
+	it.ctx, it.cancel = context.WithCancel(context.Background())
+	it.fetch()
+	return it
+}
+
+// Iterator walks the pages of a paginated endpoint, prefetching the next
+// page in the background while the caller consumes the current one.
+type Iterator struct {
+	c        *Client
+	path     string
+	elemType reflect.Type
+	params   Params
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	items []interface{}
+	idx   int
+
+	wrapper *Wrapper
+	err     error
+	done    bool
+
+	next chan fetchResult
+}
+
+type fetchResult struct {
+	items   []interface{}
+	wrapper *Wrapper
+	err     error
+}
+
+// Next advances the Iterator to the next item, fetching the next page as
+// needed. It returns false when iteration is finished, either because
+// has_more is false, the quota has been exhausted, or ctx is done.
+func (it *Iterator) Next(ctx context.Context) bool {
+	// Step past the previous item; loading a new page resets idx to 0.
+	it.idx++
+	for it.idx >= len(it.items) {
+		if it.done {
+			return false
+		}
+		select {
+		case <-ctx.Done():
+			it.err = ctx.Err()
+			return false
+		case res := <-it.next:
+			it.items = res.items
+			it.wrapper = res.wrapper
+			it.idx = 0
+			switch {
+			case res.err != nil:
+				it.err = res.err
+				it.done = true
+			case res.wrapper == nil || !res.wrapper.HasMore:
+				it.done = true
+			case it.c.QuotaRemaining() == 0:
+				// QuotaRemaining reports -1 until a request has actually
+				// reported a value, so this only fires once quota is
+				// genuinely known to be exhausted — unlike reading
+				// res.wrapper.QuotaRemaining directly, which can't tell
+				// "reported zero" from "field absent from this page".
+				it.done = true
+			}
+
+			// Prefetch the next page as soon as we start consuming this
+			// one — including when this page came back empty, so a page
+			// with has_more true but zero items (e.g. server-side
+			// filtering dropped everything) doesn't stall forever
+			// waiting on a fetch that was never issued.
+			if !it.done {
+				it.params.Page++
+				it.fetch()
+			}
+		}
+	}
+
+	return true
+}
+
+// Item returns the current item. Only valid after a call to Next that
+// returned true.
+func (it *Iterator) Item() interface{} {
+	if it.idx >= len(it.items) {
+		return nil
+	}
+	return it.items[it.idx]
+}
+
+// Err returns the first error encountered during iteration, if any.
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// Wrapper returns the Wrapper from the most recently fetched page.
+func (it *Iterator) Wrapper() *Wrapper {
+	return it.wrapper
+}
+
+// Close releases resources associated with the Iterator and stops the
+// background prefetch.
+func (it *Iterator) Close() {
+	it.cancel()
+}
+
+// fetch issues a request for the current page and delivers the result on
+// it.next once it completes.
+func (it *Iterator) fetch() {
+	it.next = make(chan fetchResult, 1)
+	go func() {
+		slicePtr := reflect.New(reflect.SliceOf(it.elemType))
+		w, err := it.c.DoContext(it.ctx, it.path, slicePtr.Interface(), &it.params)
+
+		var items []interface{}
+		if err == nil {
+			slice := slicePtr.Elem()
+			items = make([]interface{}, slice.Len())
+			for i := range items {
+				items[i] = slice.Index(i).Interface()
+			}
+		}
+
+		select {
+		case it.next <- fetchResult{items: items, wrapper: w, err: err}:
+		case <-it.ctx.Done():
+		}
+	}()
+}
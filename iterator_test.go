@@ -0,0 +1,50 @@
+package goseapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type iterTestItem struct {
+	ID int `json:"id"`
+}
+
+func TestIteratorSkipsEmptyPageWithHasMore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		switch page {
+		case "1":
+			fmt.Fprint(w, `{"items":[],"has_more":true}`)
+		case "2":
+			fmt.Fprint(w, `{"items":[{"id":7}],"has_more":false}`)
+		default:
+			t.Errorf("unexpected page requested: %q", page)
+		}
+	}))
+	defer srv.Close()
+
+	c := &Client{Root: srv.URL + "/"}
+	// Filter is set explicitly so DoContext doesn't try to auto-derive and
+	// register one against this fake server.
+	it := c.Iterate("/questions", reflect.TypeOf(iterTestItem{}), &Params{Site: StackOverflow, Filter: "default"})
+	defer it.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if !it.Next(ctx) {
+		t.Fatalf("Next returned false before reaching the non-empty page; err = %v", it.Err())
+	}
+	item := it.Item().(iterTestItem)
+	if item.ID != 7 {
+		t.Fatalf("got item %+v, want ID 7", item)
+	}
+	if it.Next(ctx) {
+		t.Fatalf("Next returned true after has_more was false")
+	}
+}
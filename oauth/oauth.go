@@ -0,0 +1,252 @@
+// Package oauth implements Stack Exchange's OAuth 2.0 authorization flows.
+//
+// https://api.stackexchange.com/docs/authentication
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// Endpoint URLs for the explicit (server-side) OAuth flow.
+const (
+	AuthorizeURL = "https://stackoverflow.com/oauth/dialog"
+	AccessURL    = "https://stackoverflow.com/oauth/access_token/json"
+)
+
+// apiRoot is the Stack Exchange API root used by the token inspection
+// helpers. Kept independent of the main goseapi package to avoid an
+// import cycle between it and TokenSource.
+const apiRoot = "https://api.stackexchange.com/2.1"
+
+// Scopes recognized by Stack Exchange's OAuth dialog.
+const (
+	ScopeReadInbox   = "read_inbox"
+	ScopeNoExpiry    = "no_expiry"
+	ScopeWriteAccess = "write_access"
+	ScopePrivateInfo = "private_info"
+)
+
+// Token is an OAuth 2.0 access token as returned by the explicit or
+// implicit grant flows.
+type Token struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires"`
+}
+
+// AuthCodeURL builds the URL to send a user to in order to begin the
+// explicit OAuth flow. scope is a comma-separated list of scope
+// constants (e.g. ScopeNoExpiry).
+func AuthCodeURL(clientID, redirect, scope, state string) string {
+	vals := url.Values{
+		"client_id":    {clientID},
+		"redirect_uri": {redirect},
+	}
+	if scope != "" {
+		vals.Set("scope", scope)
+	}
+	if state != "" {
+		vals.Set("state", state)
+	}
+	return AuthorizeURL + "?" + vals.Encode()
+}
+
+// Exchange trades an authorization code returned to redirect for an
+// access token.
+func Exchange(ctx context.Context, clientID, code, clientSecret, redirect string) (*Token, error) {
+	vals := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code":          {code},
+		"redirect_uri":  {redirect},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, AccessURL, strings.NewReader(vals.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var token Token
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Invalidate revokes an access token.
+func Invalidate(ctx context.Context, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiRoot+"/access-tokens/"+token+"/invalidate", nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("oauth: invalidate failed with status %s", resp.Status)
+	}
+	return nil
+}
+
+// TokenInfo is the result of inspecting an access token via
+// AccessTokenInfo.
+type TokenInfo struct {
+	AccessToken string   `json:"access_token"`
+	AppsAuthed  int      `json:"application_key"`
+	ExpiresOn   int      `json:"expires_on_date"`
+	Scope       []string `json:"scope"`
+}
+
+// AccessTokenInfo looks up metadata for one or more access tokens via
+// GET /access-tokens/{tokens}, mirroring the main API's {ids}-style
+// batch endpoints.
+func AccessTokenInfo(ctx context.Context, key string, tokens []string) ([]TokenInfo, error) {
+	vals := url.Values{}
+	if key != "" {
+		vals.Set("key", key)
+	}
+
+	reqURL := apiRoot + "/access-tokens/" + strings.Join(tokens, ";") + "?" + vals.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Items []TokenInfo `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// UserInfo is the subset of the /me user object returned by Me.
+type UserInfo struct {
+	UserID      int    `json:"user_id"`
+	DisplayName string `json:"display_name"`
+	Reputation  int    `json:"reputation"`
+	Link        string `json:"link"`
+}
+
+// Me looks up the authenticated user for accessToken via GET /me, the
+// standard way for an app to learn which Stack Exchange account a token
+// belongs to right after the OAuth flow completes.
+func Me(ctx context.Context, accessToken, key, site string) ([]UserInfo, error) {
+	vals := url.Values{"site": {site}}
+	if accessToken != "" {
+		vals.Set("access_token", accessToken)
+	}
+	if key != "" {
+		vals.Set("key", key)
+	}
+
+	reqURL := apiRoot + "/me?" + vals.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Items []UserInfo `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// TokenSource supplies access tokens to a Client. The returned token is
+// re-fetched on every call, so implementations that refresh or rotate
+// tokens can do so transparently.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// StaticTokenSource returns a TokenSource that always yields token
+// unchanged, for callers that obtained a non-expiring (no_expiry scope)
+// token out of band.
+func StaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token() (string, error) { return string(s), nil }
+
+// Invalidator is implemented by TokenSources that can discard their
+// current token, typically in response to the API rejecting it with a
+// 401. Client calls Invalidate before re-fetching a token to retry a
+// failed request.
+type Invalidator interface {
+	Invalidate()
+}
+
+// ReuseTokenSource wraps base so that successive calls to Token reuse
+// the last token fetched, calling base.Token again only after
+// Invalidate is called. This is useful for wrapping an Exchange result
+// so it isn't re-requested on every API call.
+func ReuseTokenSource(initial *Token, base TokenSource) TokenSource {
+	rts := &reuseTokenSource{base: base}
+	if initial != nil {
+		rts.current = initial.AccessToken
+		rts.have = true
+	}
+	return rts
+}
+
+type reuseTokenSource struct {
+	mu      sync.Mutex
+	base    TokenSource
+	current string
+	have    bool
+}
+
+func (r *reuseTokenSource) Token() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.have {
+		return r.current, nil
+	}
+	tok, err := r.base.Token()
+	if err != nil {
+		return "", err
+	}
+	r.current, r.have = tok, true
+	return tok, nil
+}
+
+func (r *reuseTokenSource) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.have = false
+}
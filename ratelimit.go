@@ -0,0 +1,178 @@
+package goseapi
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExceeded is returned by Do when the API has reported that the
+// request key has no quota remaining.
+var ErrQuotaExceeded = errors.New("goseapi: quota exceeded")
+
+// throttle tracks backoff and quota state for a Client, keyed by request
+// path and by access key so that multi-key applications sharing a Client
+// don't throttle each other. It also serializes concurrent callers
+// targeting the same path so MinInterval and backoff hold even when
+// several goroutines (e.g. Batch's chunked requests) share a Client.
+type throttle struct {
+	mu sync.Mutex
+
+	// backoffUntil is the earliest time a request to a given path may be
+	// sent again, as instructed by the API's "backoff" field.
+	backoffUntil map[string]time.Time
+
+	// lastSent is when a request to a given path was last reserved, used
+	// to measure MinInterval from the previous request rather than
+	// sleeping it unconditionally on every call.
+	lastSent map[string]time.Time
+
+	// pathLock serializes reservations for a given path so concurrent
+	// callers can't both observe "no wait needed" and send at once.
+	pathLock map[string]*sync.Mutex
+
+	// quotaRemaining is the last quota_remaining value seen for a given key.
+	quotaRemaining map[string]int
+	quotaSeen      map[string]bool
+}
+
+func (t *throttle) init() {
+	if t.backoffUntil == nil {
+		t.backoffUntil = make(map[string]time.Time)
+	}
+	if t.lastSent == nil {
+		t.lastSent = make(map[string]time.Time)
+	}
+	if t.pathLock == nil {
+		t.pathLock = make(map[string]*sync.Mutex)
+	}
+	if t.quotaRemaining == nil {
+		t.quotaRemaining = make(map[string]int)
+		t.quotaSeen = make(map[string]bool)
+	}
+}
+
+func (t *throttle) lockFor(path string) *sync.Mutex {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+	m, ok := t.pathLock[path]
+	if !ok {
+		m = &sync.Mutex{}
+		t.pathLock[path] = m
+	}
+	return m
+}
+
+// waitContext blocks, if necessary, until path is clear of any
+// previously reported backoff and at least minInterval has passed since
+// the last request to path, then reserves this moment as the new
+// lastSent so the next caller waits the right amount too. Concurrent
+// callers for the same path are serialized, so none can slip through
+// the reservation at once. It returns early with ctx.Err() if ctx is
+// canceled before the wait ends.
+func (t *throttle) waitContext(ctx context.Context, path string, minInterval time.Duration) error {
+	pathLock := t.lockFor(path)
+	pathLock.Lock()
+	defer pathLock.Unlock()
+
+	for {
+		t.mu.Lock()
+		until := t.backoffUntil[path]
+		if minInterval > 0 {
+			if next := t.lastSent[path].Add(minInterval); next.After(until) {
+				until = next
+			}
+		}
+		t.mu.Unlock()
+
+		d := time.Until(until)
+		if d <= 0 {
+			break
+		}
+		if err := sleepContext(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	t.mu.Lock()
+	t.lastSent[path] = time.Now()
+	t.mu.Unlock()
+	return nil
+}
+
+// sleepContext sleeps for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// checkQuota returns ErrQuotaExceeded if key is known to have none left.
+func (t *throttle) checkQuota(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+
+	if t.quotaSeen[key] && t.quotaRemaining[key] <= 0 {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// record stores the backoff value from w and, if quotaReported is true
+// (the response actually included a quota_remaining field), the quota
+// value too. quotaReported distinguishes "the API told us 0 remain"
+// from "this response didn't carry quota info at all".
+func (t *throttle) record(path, key string, w *Wrapper, quotaReported bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.init()
+
+	if w.Backoff > 0 {
+		t.backoffUntil[path] = time.Now().Add(time.Duration(w.Backoff) * time.Second)
+	}
+	if quotaReported {
+		t.quotaRemaining[key] = w.QuotaRemaining
+		t.quotaSeen[key] = true
+	}
+}
+
+// quotaKey identifies the quota bucket a request counts against: the
+// resolved access token (from TokenSource if set, otherwise the static
+// AccessToken field) if present, otherwise the app key, otherwise the
+// empty (anonymous) bucket. This must agree with accessToken, the
+// resolution Do actually sends on the wire, or a Client configured via
+// TokenSource would have its quota tracked under the wrong bucket.
+func (c *Client) quotaKey() string {
+	if c == nil {
+		return ""
+	}
+	if tok, err := c.accessToken(); err == nil && tok != "" {
+		return tok
+	}
+	return c.Key
+}
+
+// QuotaRemaining reports the last quota_remaining value the API returned
+// for this Client's key, or -1 if no response has reported one yet.
+func (c *Client) QuotaRemaining() int {
+	if c == nil {
+		return -1
+	}
+	c.throttle.mu.Lock()
+	defer c.throttle.mu.Unlock()
+	c.throttle.init()
+
+	key := c.quotaKey()
+	if !c.throttle.quotaSeen[key] {
+		return -1
+	}
+	return c.throttle.quotaRemaining[key]
+}
@@ -0,0 +1,61 @@
+package goseapi
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestThrottleMinIntervalMeasuredFromLastRequest(t *testing.T) {
+	var th throttle
+	ctx := context.Background()
+
+	start := time.Now()
+	if err := th.waitContext(ctx, "/questions", 200*time.Millisecond); err != nil {
+		t.Fatalf("first waitContext: %v", err)
+	}
+
+	time.Sleep(500 * time.Millisecond)
+
+	if err := th.waitContext(ctx, "/questions", 200*time.Millisecond); err != nil {
+		t.Fatalf("second waitContext: %v", err)
+	}
+	elapsed := time.Since(start)
+
+	// The 500ms gap between calls already satisfies the 200ms
+	// MinInterval, so the second call should return almost immediately,
+	// not after an additional unconditional 200ms sleep.
+	if elapsed > 700*time.Millisecond {
+		t.Fatalf("waitContext slept for an unconditional MinInterval instead of measuring it from the last request: elapsed %v", elapsed)
+	}
+}
+
+func TestThrottleSerializesConcurrentCallers(t *testing.T) {
+	var th throttle
+	ctx := context.Background()
+	const minInterval = 300 * time.Millisecond
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	returned := make([]time.Duration, 2)
+	for i := range returned {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := th.waitContext(ctx, "/questions", minInterval); err != nil {
+				t.Errorf("waitContext: %v", err)
+			}
+			returned[i] = time.Since(start)
+		}(i)
+	}
+	wg.Wait()
+
+	lo, hi := returned[0], returned[1]
+	if lo > hi {
+		lo, hi = hi, lo
+	}
+	if hi-lo < minInterval/2 {
+		t.Fatalf("concurrent waitContext calls were not serialized: returned %v apart, want at least ~%v", hi-lo, minInterval)
+	}
+}
@@ -5,12 +5,14 @@ package goseapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
 // Root is the Stack Exchange API endpoint.
@@ -97,20 +99,81 @@ func Do(path string, v interface{}, params *Params) (*Wrapper, error) {
 	return DefaultClient.Do(path, v, params)
 }
 
+// TokenSource supplies an access token for a Client to use. It matches
+// the shape of oauth.TokenSource so any value from that package plugs
+// in without Client importing it.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// Invalidator is an optional capability of a TokenSource: if a Client's
+// TokenSource implements it, the Client calls Invalidate before
+// re-fetching a token to retry a request that failed with a 401.
+type Invalidator interface {
+	Invalidate()
+}
+
 // A Client can make API requests.
 type Client struct {
 	Client *http.Client
 	Root   string
 
 	// Pass these fields if you have an OAuth 2.0 application registered with stackapps.com.
+	//
+	// AccessToken is used as-is if TokenSource is nil. Set TokenSource
+	// instead (see the oauth subpackage) when the token may need to be
+	// refreshed or invalidated on a 401 response.
 	AccessToken string
 	Key         string
+	TokenSource TokenSource
+
+	// MinInterval is the minimum amount of time to wait between requests
+	// to the same path sent by this Client; requests to different paths
+	// are not spaced against each other. When the API has also reported a
+	// backoff for the path, the request waits for whichever ends later. A
+	// zero value (the default) imposes no extra delay.
+	MinInterval time.Duration
+
+	// MaxConcurrency bounds how many chunked requests Batch issues at
+	// once. A zero value uses DefaultMaxConcurrency.
+	MaxConcurrency int
+
+	throttle throttle
+	filters  filterCache
 }
 
 var Verbose bool
 
-// Do performs an API request.
+// Do performs an API request using context.Background(). It is a thin
+// wrapper around DoContext kept for callers that don't need
+// cancellation.
 func (c *Client) Do(path string, v interface{}, params *Params) (*Wrapper, error) {
+	return c.DoContext(context.Background(), path, v, params)
+}
+
+// DoContext performs an API request, aborting early if ctx is canceled
+// or its deadline elapses. If params.Filter is unset, DoContext derives
+// and caches a minimal filter covering only the fields v will decode, so
+// callers get the bandwidth savings of a compiled filter for free; see
+// CreateFilter for the underlying mechanism.
+func (c *Client) DoContext(ctx context.Context, path string, v interface{}, params *Params) (*Wrapper, error) {
+	vals := params.values()
+	if c != nil && vals.Get("filter") == "" && v != nil {
+		if filter, err := c.filterFor(v); err == nil && filter != "" {
+			vals.Set("filter", filter)
+		}
+	}
+	return c.doValues(ctx, path, v, vals, params.Args)
+}
+
+// doValues is the shared implementation behind DoContext: it takes the
+// URL values to send directly, letting callers (such as the filter
+// subsystem) build requests Params can't express.
+func (c *Client) doValues(ctx context.Context, path string, v interface{}, vals url.Values, args []string) (*Wrapper, error) {
+	return c.doValuesRetry(ctx, path, v, vals, args, false)
+}
+
+func (c *Client) doValuesRetry(ctx context.Context, path string, v interface{}, vals url.Values, args []string, retried bool) (*Wrapper, error) {
 	// Get arguments
 	client := http.DefaultClient
 	if c != nil && c.Client != nil {
@@ -121,28 +184,74 @@ func (c *Client) Do(path string, v interface{}, params *Params) (*Wrapper, error
 		root = c.Root
 	}
 
-	// Build URL parameters
-	vals := params.values()
-	if c != nil && c.AccessToken != "" {
-		vals.Set("access_token", c.AccessToken)
+	if c != nil {
+		if err := c.throttle.checkQuota(c.quotaKey()); err != nil {
+			return nil, err
+		}
+		if err := c.throttle.waitContext(ctx, path, c.MinInterval); err != nil {
+			return nil, err
+		}
+	}
+
+	token, err := c.accessToken()
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		vals.Set("access_token", token)
 	}
 	if c != nil && c.Key != "" {
 		vals.Set("key", c.Key)
 	}
 
-	req := root + fillPlaceholders(path, params.Args) + "?" + vals.Encode()
+	reqURL := root + fillPlaceholders(path, args) + "?" + vals.Encode()
 	if Verbose {
-		fmt.Println(req)
+		fmt.Println(reqURL)
 	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	// Send request
-	resp, err := client.Get(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	// Parse response
-	return parseResponse(resp.Body, v)
+	w, quotaReported, err := parseResponse(resp.Body, v)
+	if c != nil && w != nil {
+		c.throttle.record(path, c.quotaKey(), w, quotaReported)
+	}
+
+	// A 401 usually means the access token expired or was revoked.
+	// Give a TokenSource that supports invalidation one chance to hand
+	// back a fresh token before giving up.
+	if !retried && err == nil && w.Error.ID == 401 && c != nil && c.TokenSource != nil {
+		if inv, ok := c.TokenSource.(Invalidator); ok {
+			inv.Invalidate()
+			if token, tokErr := c.accessToken(); tokErr == nil && token != "" && token != vals.Get("access_token") {
+				vals.Set("access_token", token)
+				return c.doValuesRetry(ctx, path, v, vals, args, true)
+			}
+		}
+	}
+	return w, err
+}
+
+// accessToken resolves the token to send with a request, preferring
+// TokenSource over the static AccessToken field when both are set.
+func (c *Client) accessToken() (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	if c.TokenSource != nil {
+		return c.TokenSource.Token()
+	}
+	return c.AccessToken, nil
 }
 
 func fillPlaceholders(s string, args []string) string {
@@ -185,7 +294,13 @@ func JoinIDs(ids []int) string {
 	return string(buf)
 }
 
-func parseResponse(r io.Reader, v interface{}) (*Wrapper, error) {
+// parseResponse decodes a response body into a Wrapper plus the
+// caller's v. It also reports whether the API actually included a
+// quota_remaining field, since the zero value of Wrapper.QuotaRemaining
+// is indistinguishable from "reported zero" otherwise — callers that
+// care about quota exhaustion (e.g. throttle.record) must check this
+// rather than just comparing QuotaRemaining to zero.
+func parseResponse(r io.Reader, v interface{}) (w *Wrapper, quotaReported bool, err error) {
 	var result struct {
 		Items items `json:"items"`
 
@@ -197,16 +312,23 @@ func parseResponse(r io.Reader, v interface{}) (*Wrapper, error) {
 		PageSize int  `json:"page_size"`
 		HasMore  bool `json:"has_more"`
 
-		Backoff        int `json:"backoff"`
-		QuotaMax       int `json:"quota_max"`
-		QuotaRemaining int `json:"quota_remaining"`
+		Backoff        int  `json:"backoff"`
+		QuotaMax       int  `json:"quota_max"`
+		QuotaRemaining *int `json:"quota_remaining"`
 
 		Total int    `json:"total"`
 		Type  string `json:"type"`
 	}
 	result.Items = items{v}
-	err := json.NewDecoder(r).Decode(&result)
-	return &Wrapper{
+	err = json.NewDecoder(r).Decode(&result)
+
+	var quotaRemaining int
+	if result.QuotaRemaining != nil {
+		quotaRemaining = *result.QuotaRemaining
+		quotaReported = true
+	}
+
+	w = &Wrapper{
 		Error: Error{
 			ID:      result.ErrorID,
 			Name:    result.ErrorName,
@@ -217,10 +339,11 @@ func parseResponse(r io.Reader, v interface{}) (*Wrapper, error) {
 		HasMore:        result.HasMore,
 		Backoff:        result.Backoff,
 		QuotaMax:       result.QuotaMax,
-		QuotaRemaining: result.QuotaRemaining,
+		QuotaRemaining: quotaRemaining,
 		Total:          result.Total,
 		Type:           result.Type,
-	}, err
+	}
+	return w, quotaReported, err
 }
 
 type items struct {
@@ -0,0 +1,224 @@
+package goseapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// StreamRoot is the Stack Exchange realtime endpoint.
+const StreamRoot = "ws://qa.sockets.stackexchange.com/"
+
+// Event is a single message delivered over a subscribed topic.
+//
+// Payload holds the raw JSON body for the event; its shape depends on the
+// topic (a question, an answer, a comment, ...), so callers unmarshal it
+// into the type they expect.
+type Event struct {
+	Topic   string
+	Payload json.RawMessage
+}
+
+// Topic helpers build the topic strings accepted by Subscribe.
+//
+// See http://api.stackexchange.com/docs/questions-by-site for the
+// underlying feed semantics.
+func TopicActiveQuestions(site string) string  { return fmt.Sprintf("1-questions-active-%s", site) }
+func TopicNewestQuestions(site string) string  { return fmt.Sprintf("%s-questions-newest", site) }
+func TopicQuestion(site string, id int) string { return fmt.Sprintf("%s-question-%d", site, id) }
+
+// StreamClient manages a single persistent websocket connection to the
+// Stack Exchange realtime feed and fans out decoded events to per-topic
+// subscribers.
+//
+// A StreamClient reconnects automatically when the underlying connection
+// drops; callers are not expected to recreate it on error.
+type StreamClient struct {
+	// Root overrides StreamRoot, mainly for tests.
+	Root string
+
+	// Dialer is used to establish the websocket connection. Defaults to
+	// websocket.DefaultDialer.
+	Dialer *websocket.Dialer
+
+	mu     sync.Mutex
+	conn   *websocket.Conn
+	subs   map[string][]chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	closed bool
+}
+
+// NewStreamClient creates a StreamClient ready to accept subscriptions.
+// The connection is established lazily on the first call to Subscribe.
+func NewStreamClient() *StreamClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamClient{
+		subs:   make(map[string][]chan Event),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Subscribe opens (or reuses) the websocket connection and returns a
+// channel that receives every Event published on topic. The channel is
+// closed when the StreamClient is closed.
+func (c *StreamClient) Subscribe(topic string) (<-chan Event, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, fmt.Errorf("goseapi: stream client is closed")
+	}
+	if c.conn == nil {
+		if err := c.connectLocked(); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.conn.WriteMessage(websocket.TextMessage, []byte(topic)); err != nil {
+		return nil, err
+	}
+
+	ch := make(chan Event, 16)
+	c.subs[topic] = append(c.subs[topic], ch)
+	return ch, nil
+}
+
+// Unsubscribe stops delivering events for topic on ch and closes ch.
+func (c *StreamClient) Unsubscribe(topic string, ch <-chan Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subs := c.subs[topic]
+	for i, sub := range subs {
+		if sub == ch {
+			close(sub)
+			c.subs[topic] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+}
+
+// Close tears down the websocket connection and all subscriber channels.
+func (c *StreamClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.cancel()
+
+	for _, subs := range c.subs {
+		for _, ch := range subs {
+			close(ch)
+		}
+	}
+	c.subs = nil
+
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return nil
+}
+
+// connectLocked dials the feed and starts the read loop. c.mu must be held.
+func (c *StreamClient) connectLocked() error {
+	root := StreamRoot
+	if c.Root != "" {
+		root = c.Root
+	}
+	dialer := c.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	conn, _, err := dialer.DialContext(c.ctx, root, nil)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	go c.readLoop(conn)
+	return nil
+}
+
+// readLoop decodes incoming frames and dispatches them to subscribers,
+// reconnecting with backoff if the connection drops.
+func (c *StreamClient) readLoop(conn *websocket.Conn) {
+	const reconnectDelay = 2 * time.Second
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			c.mu.Lock()
+			closed := c.closed
+			sameConn := c.conn == conn
+			c.mu.Unlock()
+			if closed || !sameConn {
+				return
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-time.After(reconnectDelay):
+			}
+
+			c.mu.Lock()
+			if c.closed {
+				c.mu.Unlock()
+				return
+			}
+			if err := c.connectLocked(); err != nil {
+				c.mu.Unlock()
+				continue
+			}
+			for topic := range c.subs {
+				_ = c.conn.WriteMessage(websocket.TextMessage, []byte(topic))
+			}
+			c.mu.Unlock()
+
+			// connectLocked already started a fresh readLoop goroutine
+			// for the new connection (gorilla/websocket allows only one
+			// reader at a time), so this goroutine's job is done.
+			return
+		}
+
+		c.dispatch(data)
+	}
+}
+
+// dispatch parses an envelope of the form {"action": topic, "data":
+// "<json-encoded string>"} — the format used by the Stack Exchange
+// realtime feed, which wraps the actual payload as a JSON string rather
+// than a bare JSON value — and routes the decoded payload to the
+// subscribers registered for that topic.
+//
+// The send to each subscriber happens while holding c.mu, the same lock
+// Unsubscribe and Close use to close those channels, so a channel can
+// never be closed concurrently with a send on it.
+func (c *StreamClient) dispatch(data []byte) {
+	var frame struct {
+		Action string `json:"action"`
+		Data   string `json:"data"`
+	}
+	if err := json.Unmarshal(data, &frame); err != nil {
+		return
+	}
+
+	ev := Event{Topic: frame.Action, Payload: json.RawMessage(frame.Data)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, ch := range c.subs[frame.Action] {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package goseapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// TestStreamClientReconnectsWithSingleReader simulates one dropped
+// connection followed by a healthy reconnect, and checks that every
+// message sent on the new connection is delivered exactly once. Before
+// the readLoop fix, the goroutine recovering from the dropped connection
+// kept looping on the *new* connection in addition to the fresh reader
+// connectLocked had already started for it, so two goroutines raced
+// gorilla/websocket's single-reader Conn and messages could be lost or
+// the process could panic.
+func TestStreamClientReconnectsWithSingleReader(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	var upgrades atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+
+		if upgrades.Add(1) == 1 {
+			// Drop the first connection immediately to force a reconnect.
+			conn.Close()
+			return
+		}
+
+		// Second connection: send a handful of messages, then idle.
+		for i := 0; i < 5; i++ {
+			frame, _ := json.Marshal(struct {
+				Action string `json:"action"`
+				Data   string `json:"data"`
+			}{Action: "stackoverflow-questions-newest", Data: `{"id":` + strconv.Itoa(i) + `}`})
+			if err := conn.WriteMessage(websocket.TextMessage, frame); err != nil {
+				return
+			}
+		}
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+	c := NewStreamClient()
+	c.Root = wsURL
+	defer c.Close()
+
+	ch, err := c.Subscribe("stackoverflow-questions-newest")
+	if err != nil {
+		t.Fatalf("Subscribe: %v", err)
+	}
+
+	seen := make(map[int]bool)
+	deadline := time.After(5 * time.Second)
+	for len(seen) < 5 {
+		select {
+		case ev := <-ch:
+			var payload struct {
+				ID int `json:"id"`
+			}
+			if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+				t.Fatalf("bad payload %s: %v", ev.Payload, err)
+			}
+			seen[payload.ID] = true
+		case <-deadline:
+			t.Fatalf("only received %d/5 messages after reconnect: %v", len(seen), seen)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package goseapi
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+)
+
+func newTestStreamClient() *StreamClient {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamClient{
+		subs:   make(map[string][]chan Event),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func TestDispatchUnwrapsStringEncodedPayload(t *testing.T) {
+	c := newTestStreamClient()
+	ch := make(chan Event, 1)
+	c.subs["stackoverflow-questions-newest"] = []chan Event{ch}
+
+	frame := `{"action":"stackoverflow-questions-newest","data":"{\"id\":42}"}`
+	c.dispatch([]byte(frame))
+
+	select {
+	case ev := <-ch:
+		var payload struct {
+			ID int `json:"id"`
+		}
+		if err := json.Unmarshal(ev.Payload, &payload); err != nil {
+			t.Fatalf("Payload did not decode as JSON: %v (payload: %s)", err, ev.Payload)
+		}
+		if payload.ID != 42 {
+			t.Fatalf("got id %d, want 42", payload.ID)
+		}
+	default:
+		t.Fatal("expected an event to be delivered")
+	}
+}
+
+func TestDispatchDoesNotRaceWithClose(t *testing.T) {
+	c := newTestStreamClient()
+	const topic = "stackoverflow-questions-newest"
+	ch := make(chan Event, 1)
+	c.subs[topic] = []chan Event{ch}
+
+	frame := []byte(`{"action":"` + topic + `","data":"{}"}`)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			c.dispatch(frame)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		c.Close()
+	}()
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package goseapi
+
+// Error holds the error_id/error_name/error_message fields the API sets
+// on a failed request. A zero-value Error (ID 0) means the request
+// succeeded.
+type Error struct {
+	ID      int
+	Name    string
+	Message string
+}
+
+func (e Error) Error() string {
+	return e.Message
+}
+
+// Wrapper holds the envelope fields common to every Stack Exchange API
+// response. The "items" array itself is decoded into the value passed to
+// Do and is not stored in the Wrapper.
+type Wrapper struct {
+	Error
+
+	Page     int
+	PageSize int
+	HasMore  bool
+
+	Backoff        int
+	QuotaMax       int
+	QuotaRemaining int
+
+	Total int
+	Type  string
+}